@@ -17,41 +17,83 @@ import (
 const (
 	commentWidth = 97
 
-	generatedFilenameExtension = ".connect.gw.go"
-	generatePackageSuffix      = "connect"
+	defaultFilenameSuffix = ".connect.gw.go"
+	defaultPackageSuffix  = "connect"
 )
 
 const (
 	contextPackage        = protogen.GoImportPath("context")
 	fmtPackage            = protogen.GoImportPath("fmt")
+	connectPackage        = protogen.GoImportPath("connectrpc.com/connect")
 	connectGatewayPackage = protogen.GoImportPath("go.vallahaye.net/connect-gateway")
 	runtimePackage        = protogen.GoImportPath("github.com/grpc-ecosystem/grpc-gateway/v2/runtime")
+	grpcPackage           = protogen.GoImportPath("google.golang.org/grpc")
 	codesPackage          = protogen.GoImportPath("google.golang.org/grpc/codes")
 	statusPackage         = protogen.GoImportPath("google.golang.org/grpc/status")
 )
 
-func Generate(plugin *protogen.Plugin, file *protogen.File) {
-	if len(file.Services) == 0 {
+// Config holds the plugin options controlling code generation, populated from
+// protoc-gen-connect-gateway's command-line flags (e.g. "--connect-gateway_opt=package_suffix=foo").
+type Config struct {
+	// PackageSuffix overrides the default "connect" suffix appended to the
+	// generated package name.
+	PackageSuffix string
+	// FilenameSuffix overrides the default ".connect.gw.go" generated filename
+	// suffix.
+	FilenameSuffix string
+	// NestedPackage controls where the generated file is written. When true
+	// (the default), it's written into a PackageSuffix-named sub-package next
+	// to the source file. When false, it's written alongside the source file,
+	// sharing its package.
+	NestedPackage bool
+	// Services, when non-empty, restricts generation to the named services;
+	// all other services in a file are skipped. An empty set generates every
+	// service in every targeted file.
+	Services map[string]struct{}
+}
+
+// NewConfig returns a Config populated with this package's defaults.
+func NewConfig() *Config {
+	return &Config{
+		PackageSuffix:  defaultPackageSuffix,
+		FilenameSuffix: defaultFilenameSuffix,
+		NestedPackage:  true,
+	}
+}
+
+func Generate(plugin *protogen.Plugin, file *protogen.File, config *Config) {
+	services := file.Services
+	if len(config.Services) > 0 {
+		filtered := make([]*protogen.Service, 0, len(services))
+		for _, service := range services {
+			if _, ok := config.Services[service.GoName]; ok {
+				filtered = append(filtered, service)
+			}
+		}
+		services = filtered
+	}
+	if len(services) == 0 {
 		return
 	}
-	file.GoPackageName += generatePackageSuffix
-	generatedFilenamePrefixToSlash := filepath.ToSlash(file.GeneratedFilenamePrefix)
-	file.GeneratedFilenamePrefix = path.Join(
-		path.Dir(generatedFilenamePrefixToSlash),
-		string(file.GoPackageName),
-		path.Base(generatedFilenamePrefixToSlash),
-	)
-	generatedFile := plugin.NewGeneratedFile(
-		file.GeneratedFilenamePrefix+generatedFilenameExtension,
-		protogen.GoImportPath(path.Join(
-			string(file.GoImportPath),
+	goImportPath := file.GoImportPath
+	if config.NestedPackage {
+		file.GoPackageName += protogen.GoPackageName(config.PackageSuffix)
+		generatedFilenamePrefixToSlash := filepath.ToSlash(file.GeneratedFilenamePrefix)
+		file.GeneratedFilenamePrefix = path.Join(
+			path.Dir(generatedFilenamePrefixToSlash),
 			string(file.GoPackageName),
-		)),
-	)
-	generatedFile.Import(file.GoImportPath)
+			path.Base(generatedFilenamePrefixToSlash),
+		)
+		goImportPath = protogen.GoImportPath(path.Join(string(goImportPath), string(file.GoPackageName)))
+	}
+	generatedFile := plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+config.FilenameSuffix, goImportPath)
+	if config.NestedPackage {
+		generatedFile.Import(file.GoImportPath)
+	}
 	generatePreamble(generatedFile, file)
-	for _, service := range file.Services {
+	for _, service := range services {
 		generateService(generatedFile, file, service)
+		generateClient(generatedFile, file, service)
 	}
 }
 
@@ -66,6 +108,8 @@ func generatePreamble(g *protogen.GeneratedFile, file *protogen.File) {
 	g.P()
 	g.P("package ", file.GoPackageName)
 	g.P()
+	g.P("const _ = ", connectGatewayPackage.Ident("SupportPackageIsVersion1"))
+	g.P()
 }
 
 func generateService(g *protogen.GeneratedFile, file *protogen.File, service *protogen.Service) {
@@ -85,9 +129,19 @@ func generateService(g *protogen.GeneratedFile, file *protogen.File, service *pr
 	g.P("type ", serviceGatewayServerGoName, " struct {")
 	g.P(file.GoImportPath.Ident(unimplementedServiceServerGoName))
 	for _, method := range service.Methods {
-		if isUnaryMethod(method) {
+		switch {
+		case isUnaryMethod(method):
+			if isDeprecatedMethod(method) {
+				generateDeprecated(g)
+			}
 			g.P(unexportedGoName(method.GoName), " ",
 				connectGatewayPackage.Ident("UnaryHandler"), "[", method.Input.GoIdent, ", ", method.Output.GoIdent, "]")
+		case isServerStreamingMethod(method):
+			if isDeprecatedMethod(method) {
+				generateDeprecated(g)
+			}
+			g.P(unexportedGoName(method.GoName), " ",
+				connectGatewayPackage.Ident("ServerStreamHandler"), "[", method.Input.GoIdent, ", ", method.Output.GoIdent, "]")
 		}
 	}
 	g.P("}")
@@ -101,10 +155,14 @@ func generateService(g *protogen.GeneratedFile, file *protogen.File, service *pr
 		"(svc ", serviceHandlerGoName, ", opts ...", connectGatewayPackage.Ident("HandlerOption"), ") *", serviceGatewayServerGoName, " {")
 	g.P("return &", serviceGatewayServerGoName, "{")
 	for _, method := range service.Methods {
-		if isUnaryMethod(method) {
-			var procedureName = fmt.Sprintf("/%s.%s/%s", method.Parent.Desc.ParentFile().Package(), method.Parent.Desc.Name(), method.Desc.Name())
+		var procedureName = fmt.Sprintf("/%s.%s/%s", method.Parent.Desc.ParentFile().Package(), method.Parent.Desc.Name(), method.Desc.Name())
+		switch {
+		case isUnaryMethod(method):
 			g.P(unexportedGoName(method.GoName), ": ",
 				connectGatewayPackage.Ident("NewUnaryHandler"), `("`, procedureName, `", svc.`, method.GoName, ", opts...),")
+		case isServerStreamingMethod(method):
+			g.P(unexportedGoName(method.GoName), ": ",
+				connectGatewayPackage.Ident("NewServerStreamHandler"), `("`, procedureName, `", svc.`, method.GoName, ", opts...),")
 		}
 	}
 	g.P("}")
@@ -112,17 +170,21 @@ func generateService(g *protogen.GeneratedFile, file *protogen.File, service *pr
 	g.P()
 	for _, method := range service.Methods {
 		var methodServerGoName = fmt.Sprintf("%s_%sServer", service.GoName, method.GoName)
-		if isUnaryMethod(method) {
+		if isDeprecatedMethod(method) {
+			generateDeprecated(g)
+		}
+		switch {
+		case isUnaryMethod(method):
 			g.P("func (s *", serviceGatewayServerGoName, ") ", method.GoName,
 				"(ctx ", contextPackage.Ident("Context"), ", req *", method.Input.GoIdent, ") (*", method.Output.GoIdent, ", error) {")
 			g.P("return s.", unexportedGoName(method.GoName), "(ctx, req)")
 			g.P("}")
-		} else if !method.Desc.IsStreamingClient() {
+		case isServerStreamingMethod(method):
 			g.P("func (s *", serviceGatewayServerGoName, ") ", method.GoName,
-				"(*", method.Input.GoIdent, ", ", file.GoImportPath.Ident(methodServerGoName), ") error {")
-			generateStreamingNotSupported(g)
+				"(req *", method.Input.GoIdent, ", stream ", file.GoImportPath.Ident(methodServerGoName), ") error {")
+			g.P("return s.", unexportedGoName(method.GoName), "(stream.Context(), req, stream.Send)")
 			g.P("}")
-		} else {
+		default:
 			g.P("func (s *", serviceGatewayServerGoName, ") ", method.GoName, "(", file.GoImportPath.Ident(methodServerGoName), ") error {")
 			generateStreamingNotSupported(g)
 			g.P("}")
@@ -143,6 +205,83 @@ func generateService(g *protogen.GeneratedFile, file *protogen.File, service *pr
 	g.P("}")
 }
 
+func generateClient(g *protogen.GeneratedFile, file *protogen.File, service *protogen.Service) {
+	var (
+		serviceClientGoName        = fmt.Sprintf("%sClient", service.GoName)
+		serviceGRPCClientGoName    = unexportedGoName(service.GoName) + "GRPCClient"
+		newServiceGRPCClientGoName = fmt.Sprintf("New%sGRPCClient", service.GoName)
+	)
+	g.P("type ", serviceGRPCClientGoName, " struct {")
+	for _, method := range service.Methods {
+		if isUnaryMethod(method) {
+			g.P(unexportedGoName(method.GoName), " *", connectPackage.Ident("Client"),
+				"[", method.Input.GoIdent, ", ", method.Output.GoIdent, "]")
+		}
+	}
+	g.P("}")
+	g.P()
+	wrapComments(g, newServiceGRPCClientGoName, " constructs a gRPC client for the ", service.GoName,
+		" service that dispatches calls through Connect clients using cc, an HTTP client, to baseURL.")
+	if isDeprecatedService(service) {
+		g.P("//")
+		generateDeprecated(g)
+	}
+	g.P("func ", newServiceGRPCClientGoName, "(cc ", connectPackage.Ident("HTTPClient"), ", baseURL string, opts ...",
+		connectPackage.Ident("ClientOption"), ") ", file.GoImportPath.Ident(serviceClientGoName), " {")
+	g.P("return &", serviceGRPCClientGoName, "{")
+	for _, method := range service.Methods {
+		if isUnaryMethod(method) {
+			var procedureName = fmt.Sprintf("/%s.%s/%s", method.Parent.Desc.ParentFile().Package(), method.Parent.Desc.Name(), method.Desc.Name())
+			g.P(unexportedGoName(method.GoName), ": ", connectPackage.Ident("NewClient"),
+				"[", method.Input.GoIdent, ", ", method.Output.GoIdent, `](cc, baseURL+"`, procedureName, `", opts...),`)
+		}
+	}
+	g.P("}")
+	g.P("}")
+	g.P()
+	for _, method := range service.Methods {
+		var methodClientGoName = fmt.Sprintf("%s_%sClient", service.GoName, method.GoName)
+		if isDeprecatedMethod(method) {
+			generateDeprecated(g)
+		}
+		switch {
+		case isUnaryMethod(method):
+			g.P("func (c *", serviceGRPCClientGoName, ") ", method.GoName, "(ctx ", contextPackage.Ident("Context"),
+				", req *", method.Input.GoIdent, ", opts ...", grpcPackage.Ident("CallOption"), ") (*", method.Output.GoIdent, ", error) {")
+			g.P("res, err := c.", unexportedGoName(method.GoName), ".CallUnary(ctx, ", connectPackage.Ident("NewRequest"), "(req))")
+			g.P("if err != nil {")
+			g.P("return nil, ", connectGatewayPackage.Ident("StatusFromError"), "(err).Err()")
+			g.P("}")
+			g.P("for _, opt := range opts {")
+			g.P("switch opt := opt.(type) {")
+			g.P("case ", grpcPackage.Ident("HeaderCallOption"), ":")
+			g.P("*opt.HeaderAddr = ", connectGatewayPackage.Ident("MetadataFromHeader"), "(res.Header())")
+			g.P("case ", grpcPackage.Ident("TrailerCallOption"), ":")
+			g.P("*opt.TrailerAddr = ", connectGatewayPackage.Ident("MetadataFromHeader"), "(res.Trailer())")
+			g.P("}")
+			g.P("}")
+			g.P("return res.Msg, nil")
+			g.P("}")
+		case isServerStreamingMethod(method):
+			g.P("func (c *", serviceGRPCClientGoName, ") ", method.GoName, "(ctx ", contextPackage.Ident("Context"),
+				", req *", method.Input.GoIdent, ", opts ...", grpcPackage.Ident("CallOption"), ") (", file.GoImportPath.Ident(methodClientGoName), ", error) {")
+			generateClientStreamingNotSupported(g)
+			g.P("}")
+		default:
+			g.P("func (c *", serviceGRPCClientGoName, ") ", method.GoName, "(ctx ", contextPackage.Ident("Context"),
+				", opts ...", grpcPackage.Ident("CallOption"), ") (", file.GoImportPath.Ident(methodClientGoName), ", error) {")
+			generateClientStreamingNotSupported(g)
+			g.P("}")
+		}
+		g.P()
+	}
+}
+
+func generateClientStreamingNotSupported(g *protogen.GeneratedFile) {
+	g.P("return nil, ", statusPackage.Ident("Error"),
+		"(", codesPackage.Ident("Unimplemented"), `, "streaming calls are not yet supported by the generated Connect-Gateway client")`)
+}
+
 func generateStreamingNotSupported(g *protogen.GeneratedFile) {
 	g.P("return ", statusPackage.Ident("Error"),
 		"(", codesPackage.Ident("Unimplemented"), `, "streaming calls are not yet supported in the in-process transport")`)
@@ -156,11 +295,20 @@ func isUnaryMethod(method *protogen.Method) bool {
 	return !method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer()
 }
 
+func isServerStreamingMethod(method *protogen.Method) bool {
+	return !method.Desc.IsStreamingClient() && method.Desc.IsStreamingServer()
+}
+
 func isDeprecatedService(service *protogen.Service) bool {
 	serviceOptions, ok := service.Desc.Options().(*descriptorpb.ServiceOptions)
 	return ok && serviceOptions.GetDeprecated()
 }
 
+func isDeprecatedMethod(method *protogen.Method) bool {
+	methodOptions, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	return ok && methodOptions.GetDeprecated()
+}
+
 func unexportedGoName(name string) string {
 	var b strings.Builder
 	b.Grow(len(name))
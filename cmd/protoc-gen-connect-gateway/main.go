@@ -0,0 +1,44 @@
+// protoc-gen-connect-gateway is a protoc plugin that generates Connect-Gateway
+// server bindings.
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"go.vallahaye.net/connect-gateway/cmd/protoc-gen-connect-gateway/internal/gengateway"
+)
+
+func main() {
+	config := gengateway.NewConfig()
+	var services string
+
+	flags := flag.NewFlagSet("protoc-gen-connect-gateway", flag.ExitOnError)
+	flags.StringVar(&config.PackageSuffix, "package_suffix", config.PackageSuffix,
+		"suffix appended to the generated package name")
+	flags.StringVar(&config.FilenameSuffix, "filename_suffix", config.FilenameSuffix,
+		"suffix appended to generated filenames")
+	flags.BoolVar(&config.NestedPackage, "nested_package", config.NestedPackage,
+		"emit generated code into a package_suffix-named sub-package instead of alongside the source file")
+	flags.StringVar(&services, "services", "",
+		"comma-separated list of services to generate; defaults to every service in a targeted file")
+
+	protogen.Options{ParamFunc: flags.Set}.Run(func(plugin *protogen.Plugin) error {
+		if services != "" {
+			config.Services = make(map[string]struct{})
+			for _, name := range strings.Split(services, ",") {
+				config.Services[name] = struct{}{}
+			}
+		}
+		plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, file := range plugin.Files {
+			if file.Generate {
+				gengateway.Generate(plugin, file, config)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package connectgateway
+
+import (
+	"errors"
+
+	"connectrpc.com/connect"
+	spbstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ErrorMapper translates an error returned by a Connect service implementation
+// into the error a generated GatewayServer method should return. Configure one
+// via WithErrorMapper; when none is set (or a nil one is), DefaultErrorMapper
+// is used.
+type ErrorMapper func(error) error
+
+// DefaultErrorMapper is the ErrorMapper used when no WithErrorMapper option is
+// supplied. It maps Connect codes onto their gRPC equivalents 1:1, copies the
+// error message verbatim, and carries over the error's details unchanged.
+// Errors that aren't *connect.Errors pass through unmodified.
+func DefaultErrorMapper(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return err
+	}
+	code, ok := connectToGRPCCodes[connectErr.Code()]
+	if !ok {
+		code = codes.Unknown
+	}
+	details := connectErr.Details()
+	anys := make([]*anypb.Any, len(details))
+	for i, detail := range details {
+		anys[i] = &anypb.Any{
+			TypeUrl: "type.googleapis.com/" + detail.Type(),
+			Value:   detail.Bytes(),
+		}
+	}
+	return status.FromProto(&spbstatus.Status{
+		Code:    int32(code),
+		Message: connectErr.Message(),
+		Details: anys,
+	}).Err()
+}
+
+// connectToGRPCCodes maps every connect.Code to its gRPC codes.Code
+// equivalent. The two enumerations share the same meanings and underlying
+// values, so this is effectively the identity function; it's kept explicit so
+// a future divergence between the wire protocols doesn't silently produce the
+// wrong status.
+var connectToGRPCCodes = map[connect.Code]codes.Code{
+	connect.CodeCanceled:           codes.Canceled,
+	connect.CodeUnknown:            codes.Unknown,
+	connect.CodeInvalidArgument:    codes.InvalidArgument,
+	connect.CodeDeadlineExceeded:   codes.DeadlineExceeded,
+	connect.CodeNotFound:           codes.NotFound,
+	connect.CodeAlreadyExists:      codes.AlreadyExists,
+	connect.CodePermissionDenied:   codes.PermissionDenied,
+	connect.CodeResourceExhausted:  codes.ResourceExhausted,
+	connect.CodeFailedPrecondition: codes.FailedPrecondition,
+	connect.CodeAborted:            codes.Aborted,
+	connect.CodeOutOfRange:         codes.OutOfRange,
+	connect.CodeUnimplemented:      codes.Unimplemented,
+	connect.CodeInternal:           codes.Internal,
+	connect.CodeUnavailable:        codes.Unavailable,
+	connect.CodeDataLoss:           codes.DataLoss,
+	connect.CodeUnauthenticated:    codes.Unauthenticated,
+}
+
+// StatusFromError converts err into a gRPC *status.Status. *connect.Errors are
+// translated using their Connect code and message; any other error is
+// reported as codes.Unknown.
+func StatusFromError(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return status.New(codes.Unknown, err.Error())
+	}
+	code, ok := connectToGRPCCodes[connectErr.Code()]
+	if !ok {
+		code = codes.Unknown
+	}
+	return status.New(code, connectErr.Message())
+}
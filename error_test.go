@@ -0,0 +1,70 @@
+package connectgateway
+
+import (
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestDefaultErrorMapper(t *testing.T) {
+	t.Run("passes through non-connect errors unmodified", func(t *testing.T) {
+		want := errors.New("boom")
+		if got := DefaultErrorMapper(want); got != want {
+			t.Errorf("DefaultErrorMapper(%v) = %v, want unchanged", want, got)
+		}
+	})
+
+	t.Run("maps code and message", func(t *testing.T) {
+		connectErr := connect.NewError(connect.CodeNotFound, errors.New("missing"))
+		err := DefaultErrorMapper(connectErr)
+		st, ok := status.FromError(err)
+		if !ok {
+			t.Fatalf("DefaultErrorMapper(%v) = %v, want a *status.Status error", connectErr, err)
+		}
+		if st.Code() != codes.NotFound {
+			t.Errorf("Code() = %v, want %v", st.Code(), codes.NotFound)
+		}
+		if st.Message() != "missing" {
+			t.Errorf("Message() = %q, want %q", st.Message(), "missing")
+		}
+	})
+
+	t.Run("carries over details", func(t *testing.T) {
+		detail, err := connect.NewErrorDetail(&anypb.Any{})
+		if err != nil {
+			t.Fatalf("NewErrorDetail: %v", err)
+		}
+		connectErr := connect.NewError(connect.CodeInternal, errors.New("oops"))
+		connectErr.AddDetail(detail)
+		st, ok := status.FromError(DefaultErrorMapper(connectErr))
+		if !ok {
+			t.Fatalf("DefaultErrorMapper did not return a *status.Status error")
+		}
+		if len(st.Proto().Details) != 1 {
+			t.Errorf("len(Details) = %d, want 1", len(st.Proto().Details))
+		}
+	})
+}
+
+func TestStatusFromError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"non-connect error", errors.New("boom"), codes.Unknown},
+		{"connect error", connect.NewError(connect.CodePermissionDenied, errors.New("nope")), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusFromError(tt.err).Code(); got != tt.want {
+				t.Errorf("StatusFromError(%v).Code() = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
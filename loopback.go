@@ -0,0 +1,60 @@
+package connectgateway
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+// loopbackListener is a net.Listener whose connections are created on demand
+// by DialContext rather than accepted from a real network socket, so that an
+// http.Server can be driven entirely in-process.
+type loopbackListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newLoopbackListener() *loopbackListener {
+	return &loopbackListener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+func (l *loopbackListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *loopbackListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *loopbackListener) Addr() net.Addr {
+	return loopbackAddr{}
+}
+
+// DialContext dials a new in-memory connection into the listener. It's meant
+// to be used as an http.Transport's DialContext.
+func (l *loopbackListener) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+type loopbackAddr struct{}
+
+func (loopbackAddr) Network() string { return "pipe" }
+func (loopbackAddr) String() string  { return "connect-gateway" }
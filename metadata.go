@@ -0,0 +1,21 @@
+package connectgateway
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataFromHeader converts an http.Header, such as one returned by a
+// Connect response, into gRPC metadata.MD. Keys are lowercased along the way
+// so that metadata.MD's canonical lookup methods (e.g. Get) find them the
+// same way they would for metadata populated by a real gRPC transport.
+func MetadataFromHeader(header http.Header) metadata.MD {
+	md := make(metadata.MD, len(header))
+	for k, v := range header {
+		k = strings.ToLower(k)
+		md[k] = append(md[k], v...)
+	}
+	return md
+}
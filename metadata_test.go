@@ -0,0 +1,20 @@
+package connectgateway
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMetadataFromHeader(t *testing.T) {
+	header := http.Header{
+		"Content-Type": []string{"application/json"},
+		"X-Custom":     []string{"a", "b"},
+	}
+	md := MetadataFromHeader(header)
+	if got := md.Get("content-type"); len(got) != 1 || got[0] != "application/json" {
+		t.Errorf(`md.Get("content-type") = %v, want ["application/json"]`, got)
+	}
+	if got := md.Get("x-custom"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf(`md.Get("x-custom") = %v, want ["a" "b"]`, got)
+	}
+}
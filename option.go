@@ -0,0 +1,66 @@
+package connectgateway
+
+import "connectrpc.com/connect"
+
+// HandlerOption configures the handlers generated for a GatewayServer.
+type HandlerOption interface {
+	applyToHandler(*handlerConfig)
+}
+
+type handlerConfig struct {
+	Interceptors []connect.Interceptor
+	ErrorMapper  ErrorMapper
+}
+
+func newHandlerConfig(opts []HandlerOption) *handlerConfig {
+	config := &handlerConfig{}
+	for _, opt := range opts {
+		opt.applyToHandler(config)
+	}
+	return config
+}
+
+// wrapUnary applies interceptors to next, in the order supplied: the first
+// interceptor in the slice acts first on a call and last on its response.
+// connect-go composes interceptors the same way internally, but doesn't
+// export that logic, so callers that can't go through a real connect.Handler
+// (like NewUnaryHandler) need their own copy.
+func wrapUnary(interceptors []connect.Interceptor, next connect.UnaryFunc) connect.UnaryFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if interceptor := interceptors[i]; interceptor != nil {
+			next = interceptor.WrapUnary(next)
+		}
+	}
+	return next
+}
+
+type interceptorsOption struct {
+	interceptors []connect.Interceptor
+}
+
+// WithInterceptors configures a handler to run the given interceptors for every
+// call, in the order supplied.
+func WithInterceptors(interceptors ...connect.Interceptor) HandlerOption {
+	return &interceptorsOption{interceptors: interceptors}
+}
+
+func (o *interceptorsOption) applyToHandler(config *handlerConfig) {
+	config.Interceptors = o.interceptors
+}
+
+type errorMapperOption struct {
+	mapper ErrorMapper
+}
+
+// WithErrorMapper configures a handler to translate errors returned by the
+// underlying Connect service implementation through mapper before returning
+// them to the caller, for both unary and streaming calls. This lets
+// domain-specific errors be reported with custom gRPC codes and details
+// instead of falling back to DefaultErrorMapper.
+func WithErrorMapper(mapper ErrorMapper) HandlerOption {
+	return &errorMapperOption{mapper: mapper}
+}
+
+func (o *errorMapperOption) applyToHandler(config *handlerConfig) {
+	config.ErrorMapper = o.mapper
+}
@@ -0,0 +1,63 @@
+package connectgateway
+
+import (
+	"context"
+	"net/http"
+
+	"connectrpc.com/connect"
+)
+
+// ServerStreamHandler adapts a Connect-generated server-streaming RPC
+// implementation so it can be called by a generated GatewayServer. send is
+// invoked once per message produced by impl; a non-nil error returned from
+// send aborts the stream.
+type ServerStreamHandler[Req, Res any] func(ctx context.Context, req *Req, send func(*Res) error) error
+
+// NewServerStreamHandler wraps impl, running it through any interceptors
+// configured via opts and translating errors through the configured
+// ErrorMapper (DefaultErrorMapper if none is set). procedure is both the
+// fully-qualified RPC name, e.g. "/acme.v1.ElizaService/Introduce", and the
+// path impl is served under.
+//
+// connect.ServerStream has no exported constructor, so impl can't be invoked
+// directly the way NewUnaryHandler invokes a unary implementation. Instead,
+// impl is served using Connect's own HTTP handler over a loopback connection,
+// and calls are dispatched to it through a Connect client: a real
+// request/response round trip, but one that never touches the network.
+func NewServerStreamHandler[Req, Res any](
+	procedure string,
+	impl func(context.Context, *connect.Request[Req], *connect.ServerStream[Res]) error,
+	opts ...HandlerOption,
+) ServerStreamHandler[Req, Res] {
+	config := newHandlerConfig(opts)
+	mapper := config.ErrorMapper
+	if mapper == nil {
+		mapper = DefaultErrorMapper
+	}
+
+	listener := newLoopbackListener()
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewServerStreamHandler(procedure, impl, connect.WithInterceptors(config.Interceptors...)))
+	go func() { _ = http.Serve(listener, mux) }()
+
+	client := connect.NewClient[Req, Res](
+		&http.Client{Transport: &http.Transport{DialContext: listener.DialContext}},
+		"http://connect-gateway.local"+procedure,
+	)
+	return func(ctx context.Context, req *Req, send func(*Res) error) error {
+		stream, err := client.CallServerStream(ctx, connect.NewRequest(req))
+		if err != nil {
+			return mapper(err)
+		}
+		defer stream.Close()
+		for stream.Receive() {
+			if err := send(stream.Msg()); err != nil {
+				return err
+			}
+		}
+		if err := stream.Err(); err != nil {
+			return mapper(err)
+		}
+		return nil
+	}
+}
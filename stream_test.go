@@ -0,0 +1,34 @@
+package connectgateway
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewServerStreamHandler(t *testing.T) {
+	impl := func(_ context.Context, req *connect.Request[wrapperspb.StringValue], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+		for i := 0; i < 3; i++ {
+			if err := stream.Send(req.Msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	handler := NewServerStreamHandler[wrapperspb.StringValue, wrapperspb.StringValue]("/test/Repeat", impl)
+
+	var got []string
+	req := wrapperspb.String("hi")
+	err := handler(context.Background(), req, func(res *wrapperspb.StringValue) error {
+		got = append(got, res.GetValue())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if len(got) != 3 || got[0] != "hi" || got[1] != "hi" || got[2] != "hi" {
+		t.Errorf("got %v, want [hi hi hi]", got)
+	}
+}
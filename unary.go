@@ -0,0 +1,39 @@
+package connectgateway
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+)
+
+// UnaryHandler adapts a Connect-generated unary RPC implementation so it can be
+// called in-process by a generated GatewayServer.
+type UnaryHandler[Req, Res any] func(ctx context.Context, req *Req) (*Res, error)
+
+// NewUnaryHandler wraps impl, running it through any interceptors configured via
+// opts and translating errors through the configured ErrorMapper
+// (DefaultErrorMapper if none is set). procedure is the fully-qualified RPC
+// name, e.g. "/acme.v1.ElizaService/Say"; it is not attached to the request
+// that interceptors see, whose Spec().Procedure is empty.
+func NewUnaryHandler[Req, Res any](
+	procedure string,
+	impl func(context.Context, *connect.Request[Req]) (*connect.Response[Res], error),
+	opts ...HandlerOption,
+) UnaryHandler[Req, Res] {
+	config := newHandlerConfig(opts)
+	unary := connect.UnaryFunc(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return impl(ctx, req.(*connect.Request[Req]))
+	})
+	unary = wrapUnary(config.Interceptors, unary)
+	mapper := config.ErrorMapper
+	if mapper == nil {
+		mapper = DefaultErrorMapper
+	}
+	return func(ctx context.Context, req *Req) (*Res, error) {
+		res, err := unary(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, mapper(err)
+		}
+		return res.(*connect.Response[Res]).Msg, nil
+	}
+}
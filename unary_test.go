@@ -0,0 +1,66 @@
+package connectgateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewUnaryHandler(t *testing.T) {
+	impl := func(_ context.Context, req *connect.Request[string]) (*connect.Response[string], error) {
+		return connect.NewResponse(req.Msg), nil
+	}
+
+	t.Run("calls through to impl", func(t *testing.T) {
+		handler := NewUnaryHandler[string, string]("/test/Echo", impl)
+		req := "hello"
+		res, err := handler(context.Background(), &req)
+		if err != nil {
+			t.Fatalf("handler() error = %v", err)
+		}
+		if *res != "hello" {
+			t.Errorf("handler() = %q, want %q", *res, "hello")
+		}
+	})
+
+	t.Run("runs interceptors in order", func(t *testing.T) {
+		var calls []string
+		newInterceptor := func(name string) connect.Interceptor {
+			return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+				return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					calls = append(calls, name)
+					return next(ctx, req)
+				}
+			})
+		}
+		handler := NewUnaryHandler[string, string]("/test/Echo", impl,
+			WithInterceptors(newInterceptor("outer"), newInterceptor("inner")))
+		req := "hello"
+		if _, err := handler(context.Background(), &req); err != nil {
+			t.Fatalf("handler() error = %v", err)
+		}
+		want := []string{"outer", "inner"}
+		if len(calls) != len(want) || calls[0] != want[0] || calls[1] != want[1] {
+			t.Errorf("calls = %v, want %v", calls, want)
+		}
+	})
+
+	t.Run("maps errors", func(t *testing.T) {
+		failingImpl := func(context.Context, *connect.Request[string]) (*connect.Response[string], error) {
+			return nil, connect.NewError(connect.CodeNotFound, errors.New("missing"))
+		}
+		handler := NewUnaryHandler[string, string]("/test/Echo", failingImpl)
+		req := "hello"
+		_, err := handler(context.Background(), &req)
+		if err == nil {
+			t.Fatal("handler() error = nil, want non-nil")
+		}
+		if got := status.Code(err); got != codes.NotFound {
+			t.Errorf("status.Code(handler() error) = %v, want %v", got, codes.NotFound)
+		}
+	})
+}
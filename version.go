@@ -0,0 +1,13 @@
+package connectgateway
+
+// SupportPackageIsVersion1 is referenced by generated code to assert that the
+// version of this runtime package is sufficiently up to date. Analogous to
+// grpc.SupportPackageIsVersionN, it follows the same semver-independent scheme:
+// it is only bumped when a change to the generator requires a corresponding
+// change in this package (or vice versa), and is not tied to the module's
+// release version.
+//
+// Removing this constant in a future version of this package produces a clear
+// compile error in code generated against an older version of the generator,
+// rather than a confusing runtime failure.
+const SupportPackageIsVersion1 = true